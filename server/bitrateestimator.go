@@ -0,0 +1,261 @@
+package server
+
+import (
+  "strconv"
+  "sync"
+  "sync/atomic"
+  "time"
+
+  "github.com/pion/rtcp"
+  "github.com/prometheus/client_golang/prometheus"
+  "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// transportCCURI is the header extension URI browsers use to tag outgoing
+// RTP packets with a transport-wide sequence number, which they then
+// reference in rtcp.TransportCCFeedback reports.
+const transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+func formatSSRC(ssrc uint32) string {
+  return strconv.FormatUint(uint64(ssrc), 10)
+}
+
+var prometheusEstimatedBitrate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+  Namespace: "peercalls",
+  Subsystem: "bwe",
+  Name:      "estimated_bitrate_bps",
+  Help:      "Estimated available bitrate for a remote track, aggregated across its subscribers",
+}, []string{"ssrc"})
+
+// bitrateStaleAfter bounds how long a bandwidth estimate is trusted after
+// its last update. A subscriber that stops reporting (e.g. because it left,
+// or the network went quiet) should not keep capping the publisher
+// forever, so a stale cell is treated the same as "no estimate yet" -
+// effectively unbounded.
+const bitrateStaleAfter = 8 * time.Second
+
+// rembUpdateInterval is how often a synthetic REMB is sent back to each
+// publisher, reflecting the current minimum estimate across its
+// subscribers.
+const rembUpdateInterval = time.Second
+
+// bitrateCell is a single timestamped bandwidth estimate.
+type bitrateCell struct {
+  mu    sync.Mutex
+  value uint64
+  at    time.Time
+}
+
+func (c *bitrateCell) set(value uint64) {
+  c.mu.Lock()
+  c.value = value
+  c.at = time.Now()
+  c.mu.Unlock()
+}
+
+func (c *bitrateCell) get() (uint64, bool) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if c.at.IsZero() || time.Since(c.at) > bitrateStaleAfter {
+    return 0, false
+  }
+
+  return c.value, true
+}
+
+// bitrateEstimator tracks, for a single outbound (subscriber-facing) RTP
+// track, how much bandwidth that subscriber has reported being able to
+// receive. It combines explicit REMB reports with a throughput estimate
+// derived from TWCC feedback and the bytes we actually sent in between
+// feedback reports.
+type bitrateEstimator struct {
+  remb bitrateCell
+  twcc bitrateCell
+
+  sentBytes uint64 // atomic, bytes written since lastTWCC
+
+  lastTWCCMu sync.Mutex
+  lastTWCC   time.Time
+}
+
+func newBitrateEstimator() *bitrateEstimator {
+  return &bitrateEstimator{}
+}
+
+// addSentBytes is called from the write path so updateTWCC can turn
+// "fraction of packets acknowledged as received" into an actual bits-per-
+// second figure.
+func (e *bitrateEstimator) addSentBytes(n int) {
+  atomic.AddUint64(&e.sentBytes, uint64(n))
+}
+
+func (e *bitrateEstimator) updateREMB(remb *rtcp.ReceiverEstimatedMaximumBitrate) {
+  e.remb.set(uint64(remb.Bitrate))
+}
+
+// updateTWCC derives a throughput estimate from the fraction of packets
+// sent since the last feedback report that were acknowledged as received,
+// scaled by how many bytes were actually sent in that window. This is a
+// simplification of full TWCC-based bandwidth estimation (no RTT-aware
+// pacing, no separate min/max bounds), sufficient to cap an encoder before
+// it ramps into congestion.
+func (e *bitrateEstimator) updateTWCC(fb *rtcp.TransportCCFeedback) {
+  e.lastTWCCMu.Lock()
+  last := e.lastTWCC
+  e.lastTWCC = time.Now()
+  e.lastTWCCMu.Unlock()
+
+  sent := atomic.SwapUint64(&e.sentBytes, 0)
+
+  if last.IsZero() || sent == 0 {
+    return
+  }
+
+  window := time.Since(last)
+  if window <= 0 {
+    return
+  }
+
+  total := len(fb.RecvDeltas)
+  if total == 0 {
+    return
+  }
+
+  received := 0
+
+  for _, delta := range fb.RecvDeltas {
+    if delta != nil && delta.Type != rtcp.TypeTCCPacketNotReceived {
+      received++
+    }
+  }
+
+  if received == 0 {
+    return
+  }
+
+  fraction := float64(received) / float64(total)
+  bps := uint64(float64(sent) * 8 * fraction / window.Seconds())
+
+  e.twcc.set(bps)
+}
+
+// estimate returns the lower of the REMB- and TWCC-derived estimates,
+// since either one reporting congestion should cap the publisher. It
+// returns false if neither has a fresh value.
+func (e *bitrateEstimator) estimate() (uint64, bool) {
+  rembVal, rembOK := e.remb.get()
+  twccVal, twccOK := e.twcc.get()
+
+  switch {
+  case rembOK && twccOK:
+    if twccVal < rembVal {
+      return twccVal, true
+    }
+
+    return rembVal, true
+  case rembOK:
+    return rembVal, true
+  case twccOK:
+    return twccVal, true
+  default:
+    return 0, false
+  }
+}
+
+// minBitrateEstimate returns the lowest fresh estimate among estimators, and
+// false if none of them have one. The estimators themselves are owned and
+// registered by the publishing WebRTCTransport (see its bitrateEstimators
+// field for why); this is deliberately a pure function over an
+// already-collected slice so that ownership/locking stays with the
+// transport.
+func minBitrateEstimate(estimators []*bitrateEstimator) (uint64, bool) {
+  var (
+    min   uint64
+    found bool
+  )
+
+  for _, estimator := range estimators {
+    value, ok := estimator.estimate()
+    if !ok {
+      continue
+    }
+
+    if !found || value < min {
+      min = value
+      found = true
+    }
+  }
+
+  return min, found
+}
+
+// bitrateCoordinator periodically reflects the lowest recent bandwidth
+// estimate across every subscriber of one remote track back to its
+// publisher as a synthetic REMB, so a single congested subscriber caps
+// what the encoder sends for everyone, not just itself.
+type bitrateCoordinator struct {
+  ssrc      uint32
+  estimate  func() (uint64, bool)
+  writeRTCP func([]rtcp.Packet) error
+  log       Logger
+  clientID  string
+
+  stopCh chan struct{}
+}
+
+func newBitrateCoordinator(
+  log Logger, clientID string, writeRTCP func([]rtcp.Packet) error, ssrc uint32, estimate func() (uint64, bool),
+) *bitrateCoordinator {
+  return &bitrateCoordinator{
+    ssrc:      ssrc,
+    estimate:  estimate,
+    writeRTCP: writeRTCP,
+    log:       log,
+    clientID:  clientID,
+    stopCh:    make(chan struct{}),
+  }
+}
+
+func (c *bitrateCoordinator) start(wg *sync.WaitGroup) {
+  wg.Add(1)
+
+  go func() {
+    defer wg.Done()
+
+    ticker := time.NewTicker(rembUpdateInterval)
+    defer ticker.Stop()
+
+    for {
+      select {
+      case <-ticker.C:
+        c.tick()
+      case <-c.stopCh:
+        return
+      }
+    }
+  }()
+}
+
+func (c *bitrateCoordinator) stop() {
+  close(c.stopCh)
+}
+
+func (c *bitrateCoordinator) tick() {
+  bps, ok := c.estimate()
+  if !ok {
+    return
+  }
+
+  prometheusEstimatedBitrate.WithLabelValues(formatSSRC(c.ssrc)).Set(float64(bps))
+
+  err := c.writeRTCP([]rtcp.Packet{
+    &rtcp.ReceiverEstimatedMaximumBitrate{
+      Bitrate: float32(bps),
+      SSRCs:   []uint32{c.ssrc},
+    },
+  })
+  if err != nil {
+    c.log.Printf("[%s] Error sending synthetic REMB for ssrc %d: %s", c.clientID, c.ssrc, err)
+  }
+}
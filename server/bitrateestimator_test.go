@@ -0,0 +1,67 @@
+package server
+
+import (
+  "testing"
+  "time"
+)
+
+func TestBitrateCell_StaleAfterTimeout(t *testing.T) {
+  var c bitrateCell
+
+  if _, ok := c.get(); ok {
+    t.Fatalf("expected no value before the first set")
+  }
+
+  c.set(1000)
+
+  if got, ok := c.get(); !ok || got != 1000 {
+    t.Fatalf("expected (1000, true), got (%d, %v)", got, ok)
+  }
+
+  c.at = time.Now().Add(-bitrateStaleAfter - time.Second)
+
+  if _, ok := c.get(); ok {
+    t.Fatalf("expected stale value to be reported as absent")
+  }
+}
+
+func TestBitrateEstimator_LowerOfREMBAndTWCC(t *testing.T) {
+  e := newBitrateEstimator()
+
+  if _, ok := e.estimate(); ok {
+    t.Fatalf("expected no estimate before either estimator reports")
+  }
+
+  e.remb.set(2_000_000)
+
+  got, ok := e.estimate()
+  if !ok || got != 2_000_000 {
+    t.Fatalf("expected (2000000, true) with only REMB set, got (%d, %v)", got, ok)
+  }
+
+  e.twcc.set(1_500_000)
+
+  got, ok = e.estimate()
+  if !ok || got != 1_500_000 {
+    t.Fatalf("expected the lower TWCC estimate to win, got (%d, %v)", got, ok)
+  }
+}
+
+func TestMinBitrateEstimate(t *testing.T) {
+  if _, ok := minBitrateEstimate(nil); ok {
+    t.Fatalf("expected no estimate across zero estimators")
+  }
+
+  stale := newBitrateEstimator()
+
+  low := newBitrateEstimator()
+  low.remb.set(500_000)
+
+  high := newBitrateEstimator()
+  high.remb.set(1_000_000)
+
+  got, ok := minBitrateEstimate([]*bitrateEstimator{stale, low, high})
+  if !ok || got != 500_000 {
+    t.Fatalf("expected the lowest fresh estimate (500000), got (%d, %v)", got, ok)
+  }
+}
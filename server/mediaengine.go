@@ -0,0 +1,199 @@
+package server
+
+import (
+  "strings"
+  "sync"
+
+  "github.com/pion/sdp/v3"
+  "github.com/pion/webrtc/v3"
+)
+
+// RegisterCodecs configures mediaEngine with every codec the SFU is able to
+// forward. Unlike a client-side media engine, this does not need to pick a
+// single preferred codec: it registers VP8, VP9 and H264 for video (so
+// H264-only clients such as Safari/iOS, and VP9 publishers, both work) and
+// Opus and G722 for audio, and lets per-connection SDP negotiation pick
+// whichever of these both ends support.
+func RegisterCodecs(mediaEngine *webrtc.MediaEngine) {
+  videoRTCPFeedback := []webrtc.RTCPFeedback{
+    {
+      Type: webrtc.TypeRTCPFBGoogREMB,
+    },
+    // "transport-cc" feeds the per-subscriber bitrateEstimator in addition
+    // to goog-remb, so estimates keep working against browsers that only
+    // send one of the two.
+    {
+      Type: webrtc.TypeRTCPFBTransportCC,
+    },
+    // https://tools.ietf.org/html/rfc4585#section-4.2
+    // "pli" indicates the use of Picture Loss Indication feedback as
+    // defined in Section 6.3.1.
+    {
+      Type:      webrtc.TypeRTCPFBNACK,
+      Parameter: "pli",
+    },
+    // The feedback type "nack", without parameters, indicates use of the
+    // Generic NACK feedback format as defined in Section 6.2.1. This is
+    // registered unconditionally so that remote peers actually send NACKs
+    // for the packet cache to serve.
+    {
+      Type:      webrtc.TypeRTCPFBNACK,
+      Parameter: "",
+    },
+  }
+
+  videoCodecs := []webrtc.RTPCodecParameters{
+    {
+      RTPCodecCapability: webrtc.RTPCodecCapability{
+        MimeType: webrtc.MimeTypeVP8, ClockRate: 90000, RTCPFeedback: videoRTCPFeedback,
+      },
+      PayloadType: 96,
+    },
+    {
+      RTPCodecCapability: webrtc.RTPCodecCapability{
+        MimeType: webrtc.MimeTypeVP9, ClockRate: 90000, SDPFmtpLine: "profile-id=0", RTCPFeedback: videoRTCPFeedback,
+      },
+      PayloadType: 98,
+    },
+    {
+      RTPCodecCapability: webrtc.RTPCodecCapability{
+        MimeType:     webrtc.MimeTypeH264,
+        ClockRate:    90000,
+        SDPFmtpLine:  "profile-level-id=42e01f;level-asymmetry-allowed=1;packetization-mode=1",
+        RTCPFeedback: videoRTCPFeedback,
+      },
+      PayloadType: 102,
+    },
+  }
+
+  for _, codec := range videoCodecs {
+    if err := mediaEngine.RegisterCodec(codec, webrtc.RTPCodecTypeVideo); err != nil {
+      panic(err)
+    }
+
+    registerSupportedCodec(codec.RTPCodecCapability)
+  }
+
+  audioCodecs := []webrtc.RTPCodecParameters{
+    {
+      RTPCodecCapability: webrtc.RTPCodecCapability{
+        MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2, SDPFmtpLine: "minptime=10;useinbandfec=1",
+      },
+      PayloadType: 111,
+    },
+    {
+      RTPCodecCapability: webrtc.RTPCodecCapability{
+        MimeType: webrtc.MimeTypeG722, ClockRate: 8000,
+      },
+      PayloadType: 9,
+    },
+  }
+
+  for _, codec := range audioCodecs {
+    if err := mediaEngine.RegisterCodec(codec, webrtc.RTPCodecTypeAudio); err != nil {
+      panic(err)
+    }
+
+    registerSupportedCodec(codec.RTPCodecCapability)
+  }
+
+  if err := mediaEngine.RegisterHeaderExtension(
+    webrtc.RTPHeaderExtensionCapability{URI: transportCCURI}, webrtc.RTPCodecTypeVideo,
+  ); err != nil {
+    panic(err)
+  }
+
+  if err := mediaEngine.RegisterHeaderExtension(
+    webrtc.RTPHeaderExtensionCapability{URI: transportCCURI}, webrtc.RTPCodecTypeAudio,
+  ); err != nil {
+    panic(err)
+  }
+}
+
+// supportedCodecs mirrors the codecs registered via RegisterCodecs, keyed
+// by lowercased MIME type, so AddTrack can reject a publisher's codec
+// before wiring up a subscriber track for it instead of silently producing
+// undecodable media.
+var (
+  supportedCodecsMu sync.Mutex
+  supportedCodecs   = map[string]webrtc.RTPCodecCapability{}
+)
+
+func registerSupportedCodec(capability webrtc.RTPCodecCapability) {
+  supportedCodecsMu.Lock()
+  supportedCodecs[strings.ToLower(capability.MimeType)] = capability
+  supportedCodecsMu.Unlock()
+}
+
+func isSupportedCodec(mimeType string) bool {
+  supportedCodecsMu.Lock()
+  _, ok := supportedCodecs[strings.ToLower(mimeType)]
+  supportedCodecsMu.Unlock()
+
+  return ok
+}
+
+// subscriberSupportsCodec reports whether mimeType was actually negotiated
+// by this particular subscriber, by inspecting the rtpmap lines of its own
+// most recent remote description - unlike isSupportedCodec, which only
+// checks against the SFU's own global codec list and so can never reject
+// anything a publisher was allowed to send in the first place.
+//
+// remoteDescription is nil the very first time a subscriber's peer
+// connection negotiates (there is no prior offer/answer to inspect yet), and
+// every WebRTCTransportFactory transport is an initiator, so the very first
+// video or audio track ever forwarded to a subscriber is added before that
+// subscriber has negotiated an m= section of that kind at all - there is
+// nothing subscriber-specific to check yet either way. Both of these cases
+// report true, deferring entirely to isSupportedCodec, and only a media
+// section that was actually negotiated without mimeType's encoding counts
+// as a real mismatch.
+func subscriberSupportsCodec(remoteDescription *webrtc.SessionDescription, mimeType string) bool {
+  if remoteDescription == nil {
+    return true
+  }
+
+  parts := strings.SplitN(mimeType, "/", 2)
+  if len(parts) != 2 {
+    return true
+  }
+
+  media, name := strings.ToLower(parts[0]), parts[1]
+
+  var parsed sdp.SessionDescription
+  if err := parsed.Unmarshal([]byte(remoteDescription.SDP)); err != nil {
+    return true
+  }
+
+  sawMediaSection := false
+
+  for _, md := range parsed.MediaDescriptions {
+    if md.MediaName.Media != media {
+      continue
+    }
+
+    sawMediaSection = true
+
+    for _, attr := range md.Attributes {
+      if attr.Key != "rtpmap" {
+        continue
+      }
+
+      fields := strings.SplitN(attr.Value, " ", 2)
+      if len(fields) != 2 {
+        continue
+      }
+
+      encoding := strings.SplitN(fields[1], "/", 2)[0]
+      if strings.EqualFold(encoding, name) {
+        return true
+      }
+    }
+  }
+
+  // No m= section of this kind has been negotiated yet (e.g. a subscriber
+  // that has so far only negotiated a data channel) - there is nothing to
+  // reject against, so defer to isSupportedCodec rather than treating an
+  // absent section the same as one that was negotiated without this codec.
+  return !sawMediaSection
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+  "testing"
+
+  "github.com/pion/webrtc/v3"
+)
+
+const testSubscriberSDP = `v=0
+o=- 0 0 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=audio 9 UDP/TLS/RTP/SAVPF 111
+c=IN IP4 0.0.0.0
+a=rtpmap:111 opus/48000/2
+m=application 9 DTLS/SCTP 5000
+c=IN IP4 0.0.0.0
+`
+
+func TestSubscriberSupportsCodec_NilRemoteDescription(t *testing.T) {
+  if !subscriberSupportsCodec(nil, webrtc.MimeTypeVP8) {
+    t.Fatalf("expected a nil remote description (no prior negotiation) to defer to isSupportedCodec")
+  }
+}
+
+func TestSubscriberSupportsCodec_UnnegotiatedMediaKind(t *testing.T) {
+  desc := &webrtc.SessionDescription{SDP: testSubscriberSDP}
+
+  if !subscriberSupportsCodec(desc, webrtc.MimeTypeVP8) {
+    t.Fatalf("expected a media kind with no negotiated m= section yet (here: video) to defer to isSupportedCodec")
+  }
+}
+
+func TestSubscriberSupportsCodec_Matching(t *testing.T) {
+  desc := &webrtc.SessionDescription{SDP: testSubscriberSDP}
+
+  if !subscriberSupportsCodec(desc, webrtc.MimeTypeOpus) {
+    t.Fatalf("expected the negotiated opus rtpmap to satisfy audio/opus")
+  }
+}
+
+func TestSubscriberSupportsCodec_NonMatching(t *testing.T) {
+  desc := &webrtc.SessionDescription{SDP: testSubscriberSDP}
+
+  if subscriberSupportsCodec(desc, "audio/G722") {
+    t.Fatalf("expected a negotiated audio section without a G722 rtpmap to reject G722")
+  }
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+  "sync"
+  "time"
+
+  "github.com/pion/rtp"
+  "github.com/prometheus/client_golang/prometheus"
+  "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// packetCacheSize is the number of packets retained per remote track. It
+// must be a power of two so modular sequence-number arithmetic can use a
+// plain mask instead of an expensive div/mod.
+const packetCacheSize = 512
+
+// packetCacheEntry holds enough of an RTP packet to retransmit it verbatim
+// on a NACK, without keeping the whole decoded packet (and its allocations)
+// around for the lifetime of the cache slot.
+type packetCacheEntry struct {
+  valid   bool
+  seq     uint16
+  payload []byte
+  size    int
+  arrival time.Time
+}
+
+// packetCache is a fixed-size ring buffer of recently seen RTP packets for
+// a single remote track, keyed by sequence number so retransmission lookups
+// are O(1) and bounded in memory regardless of how fast the track sends.
+type packetCache struct {
+  mu      sync.Mutex
+  entries [packetCacheSize]packetCacheEntry
+}
+
+func newPacketCache() *packetCache {
+  return &packetCache{}
+}
+
+// add stores a copy of pkt, keyed by its sequence number. Older packets in
+// the same slot (512 sequence numbers ago) are silently overwritten.
+func (c *packetCache) add(pkt *rtp.Packet) {
+  payload, err := pkt.Marshal()
+  if err != nil {
+    return
+  }
+
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  c.entries[pkt.SequenceNumber%packetCacheSize] = packetCacheEntry{
+    valid:   true,
+    seq:     pkt.SequenceNumber,
+    payload: payload,
+    size:    len(payload),
+    arrival: time.Now(),
+  }
+}
+
+// get looks up the packet with sequence number seq, returning false if it
+// was never cached or has since been evicted by a wraparound.
+func (c *packetCache) get(seq uint16) (*rtp.Packet, bool) {
+  c.mu.Lock()
+  entry := c.entries[seq%packetCacheSize]
+  c.mu.Unlock()
+
+  if !entry.valid || entry.seq != seq {
+    return nil, false
+  }
+
+  pkt := &rtp.Packet{}
+  if err := pkt.Unmarshal(entry.payload); err != nil {
+    return nil, false
+  }
+
+  return pkt, true
+}
+
+var (
+  prometheusPacketCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+    Namespace: "peercalls",
+    Subsystem: "nack",
+    Name:      "packet_cache_hits_total",
+    Help:      "Total number of NACKed packets found in the packet cache",
+  })
+  prometheusPacketCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+    Namespace: "peercalls",
+    Subsystem: "nack",
+    Name:      "packet_cache_misses_total",
+    Help:      "Total number of NACKed packets that were missing from the packet cache",
+  })
+  prometheusPacketsRetransmitted = promauto.NewCounter(prometheus.CounterOpts{
+    Namespace: "peercalls",
+    Subsystem: "nack",
+    Name:      "packets_retransmitted_total",
+    Help:      "Total number of RTP packets retransmitted in response to a NACK",
+  })
+)
@@ -0,0 +1,83 @@
+package server
+
+import (
+  "testing"
+
+  "github.com/pion/rtp"
+)
+
+func TestPacketCache_AddGet(t *testing.T) {
+  c := newPacketCache()
+
+  pkt := &rtp.Packet{
+    Header:  rtp.Header{SequenceNumber: 100, Timestamp: 1000},
+    Payload: []byte{1, 2, 3},
+  }
+
+  c.add(pkt)
+
+  got, ok := c.get(100)
+  if !ok {
+    t.Fatalf("expected packet 100 to be cached")
+  }
+
+  if got.SequenceNumber != 100 {
+    t.Fatalf("expected sequence number 100, got %d", got.SequenceNumber)
+  }
+}
+
+func TestPacketCache_MissingSequence(t *testing.T) {
+  c := newPacketCache()
+
+  if _, ok := c.get(42); ok {
+    t.Fatalf("expected no packet cached for seq 42")
+  }
+}
+
+// TestPacketCache_Wraparound verifies that a sequence number is only
+// considered cached if it still occupies its ring slot: once
+// packetCacheSize newer sequence numbers have landed in the same slot, the
+// older one must be reported as evicted rather than returning stale data.
+func TestPacketCache_Wraparound(t *testing.T) {
+  c := newPacketCache()
+
+  first := &rtp.Packet{Header: rtp.Header{SequenceNumber: 10}, Payload: []byte{1}}
+  c.add(first)
+
+  overwrite := &rtp.Packet{Header: rtp.Header{SequenceNumber: 10 + packetCacheSize}, Payload: []byte{2}}
+  c.add(overwrite)
+
+  if _, ok := c.get(10); ok {
+    t.Fatalf("expected seq 10 to be evicted by wraparound")
+  }
+
+  got, ok := c.get(10 + packetCacheSize)
+  if !ok {
+    t.Fatalf("expected seq %d to be cached", 10+packetCacheSize)
+  }
+
+  if got.SequenceNumber != 10+packetCacheSize {
+    t.Fatalf("expected sequence number %d, got %d", 10+packetCacheSize, got.SequenceNumber)
+  }
+}
+
+// TestPacketCache_SequenceNumberWraparound covers the uint16 rollover at
+// 65535 -> 0, which must hash into adjacent ring slots rather than
+// colliding or panicking.
+func TestPacketCache_SequenceNumberWraparound(t *testing.T) {
+  c := newPacketCache()
+
+  last := &rtp.Packet{Header: rtp.Header{SequenceNumber: 65535}, Payload: []byte{1}}
+  first := &rtp.Packet{Header: rtp.Header{SequenceNumber: 0}, Payload: []byte{2}}
+
+  c.add(last)
+  c.add(first)
+
+  if got, ok := c.get(65535); !ok || got.SequenceNumber != 65535 {
+    t.Fatalf("expected seq 65535 to still be cached, got %v, %v", got, ok)
+  }
+
+  if got, ok := c.get(0); !ok || got.SequenceNumber != 0 {
+    t.Fatalf("expected seq 0 to be cached, got %v, %v", got, ok)
+  }
+}
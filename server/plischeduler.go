@@ -0,0 +1,101 @@
+package server
+
+import (
+  "sync"
+  "time"
+
+  "github.com/pion/rtcp"
+)
+
+// defaultPLIInterval is used when NetworkConfigSFU.PLIInterval is not set
+// (zero value), keeping the minimum-keyframe-request behaviour even for
+// configs written before this option existed.
+const defaultPLIInterval = 3 * time.Second
+
+// minPLIInterval rate-limits keyframe requests so that a burst of
+// subscribers joining at once (or repeated decoder errors) doesn't turn
+// into a PLI storm back at the publisher.
+const minPLIInterval = 500 * time.Millisecond
+
+// pliScheduler periodically (and on demand) requests a keyframe from the
+// publisher of a single remote track, so that late subscribers don't have
+// to wait for the publisher's encoder to emit one on its own schedule.
+type pliScheduler struct {
+  mu       sync.Mutex
+  lastPLI  time.Time
+  interval time.Duration
+
+  mediaSSRC uint32
+  writeRTCP func([]rtcp.Packet) error
+  log       Logger
+  clientID  string
+
+  stopCh chan struct{}
+}
+
+func newPLIScheduler(
+  log Logger, clientID string, writeRTCP func([]rtcp.Packet) error, mediaSSRC uint32, interval time.Duration,
+) *pliScheduler {
+  if interval <= 0 {
+    interval = defaultPLIInterval
+  }
+
+  return &pliScheduler{
+    interval:  interval,
+    mediaSSRC: mediaSSRC,
+    writeRTCP: writeRTCP,
+    log:       log,
+    clientID:  clientID,
+    stopCh:    make(chan struct{}),
+  }
+}
+
+// start kicks off the periodic PLI loop in the background. It always
+// requests one keyframe immediately so late joiners don't wait a full
+// interval for the first one.
+func (s *pliScheduler) start(wg *sync.WaitGroup) {
+  s.request()
+
+  wg.Add(1)
+
+  go func() {
+    defer wg.Done()
+
+    ticker := time.NewTicker(s.interval)
+    defer ticker.Stop()
+
+    for {
+      select {
+      case <-ticker.C:
+        s.request()
+      case <-s.stopCh:
+        return
+      }
+    }
+  }()
+}
+
+func (s *pliScheduler) stop() {
+  close(s.stopCh)
+}
+
+// request sends a PictureLossIndication for the track, unless one was sent
+// less than minPLIInterval ago.
+func (s *pliScheduler) request() {
+  s.mu.Lock()
+  since := time.Since(s.lastPLI)
+  if !s.lastPLI.IsZero() && since < minPLIInterval {
+    s.mu.Unlock()
+
+    return
+  }
+  s.lastPLI = time.Now()
+  s.mu.Unlock()
+
+  err := s.writeRTCP([]rtcp.Packet{
+    &rtcp.PictureLossIndication{MediaSSRC: s.mediaSSRC},
+  })
+  if err != nil {
+    s.log.Printf("[%s] Error sending PLI for ssrc %d: %s", s.clientID, s.mediaSSRC, err)
+  }
+}
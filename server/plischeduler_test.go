@@ -0,0 +1,32 @@
+package server
+
+import (
+  "testing"
+  "time"
+
+  "github.com/pion/rtcp"
+)
+
+func TestPLIScheduler_RequestRateLimited(t *testing.T) {
+  var calls int
+
+  s := newPLIScheduler(nil, "client", func(pkts []rtcp.Packet) error {
+    calls++
+
+    return nil
+  }, 1234, time.Second)
+
+  s.request()
+  s.request()
+
+  if calls != 1 {
+    t.Fatalf("expected the second immediate request to be rate-limited, got %d calls", calls)
+  }
+
+  s.lastPLI = time.Now().Add(-minPLIInterval - time.Millisecond)
+  s.request()
+
+  if calls != 2 {
+    t.Fatalf("expected a request after minPLIInterval has elapsed to go through, got %d calls", calls)
+  }
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+  "strconv"
+  "strings"
+
+  "github.com/juju/errors"
+  "github.com/pion/sdp/v3"
+)
+
+// defaultMaxVideoBitrate and defaultMaxAudioBitrate are used when
+// NetworkConfigSFU.MaxVideoBitrate/MaxAudioBitrate are not set (zero
+// value).
+const (
+  defaultMaxVideoBitrate uint64 = 2_000_000
+  defaultMaxAudioBitrate uint64 = 64_000
+)
+
+// addBandwidthHints appends a b=TIAS:<bps> line to every m=video section
+// (capped at maxVideoBitrate) and m=audio section (capped at
+// maxAudioBitrate) of sdpText, giving the remote encoder an explicit upper
+// bound instead of letting it ramp up until it congests the link.
+//
+// trackMaxBitrate is consulted per m= section, keyed by that section's own
+// SSRC (from its "a=ssrc" attribute): when it holds an override for that
+// SSRC, the override wins over the per-kind default. This lets a single
+// subscriber connection carrying several publishers' tracks throttle one
+// of them (see WebRTCTransport.SetMaxBitrate) without clobbering the hint
+// sent for every other track of the same kind.
+func addBandwidthHints(
+  sdpText string, maxVideoBitrate, maxAudioBitrate uint64, trackMaxBitrate map[uint32]uint64,
+) (string, error) {
+  var parsed sdp.SessionDescription
+  if err := parsed.Unmarshal([]byte(sdpText)); err != nil {
+    return "", errors.Annotate(err, "unmarshal sdp")
+  }
+
+  for _, md := range parsed.MediaDescriptions {
+    var bps uint64
+
+    switch md.MediaName.Media {
+    case "video":
+      bps = maxVideoBitrate
+    case "audio":
+      bps = maxAudioBitrate
+    default:
+      continue
+    }
+
+    if ssrc, ok := mediaSSRC(md); ok {
+      if override, ok := trackMaxBitrate[ssrc]; ok {
+        bps = override
+      }
+    }
+
+    md.Bandwidth = append(withoutTIAS(md.Bandwidth), sdp.Bandwidth{
+      Type:      "TIAS",
+      Bandwidth: bps,
+    })
+  }
+
+  out, err := parsed.Marshal()
+  if err != nil {
+    return "", errors.Annotate(err, "marshal sdp")
+  }
+
+  return string(out), nil
+}
+
+// mediaSSRC extracts the SSRC a media section describes from its first
+// "a=ssrc:<ssrc> ..." attribute (https://tools.ietf.org/html/rfc5576),
+// reporting false if the section has none (e.g. a recvonly m= section
+// that hasn't been assigned a local SSRC yet).
+func mediaSSRC(md *sdp.MediaDescription) (uint32, bool) {
+  for _, attr := range md.Attributes {
+    if attr.Key != "ssrc" {
+      continue
+    }
+
+    fields := strings.SplitN(attr.Value, " ", 2)
+
+    ssrc, err := strconv.ParseUint(fields[0], 10, 32)
+    if err != nil {
+      continue
+    }
+
+    return uint32(ssrc), true
+  }
+
+  return 0, false
+}
+
+// withoutTIAS drops any pre-existing TIAS line so re-running
+// addBandwidthHints on an already-hinted SDP (e.g. after SetMaxBitrate
+// triggers a renegotiation) doesn't accumulate duplicates.
+func withoutTIAS(bandwidths []sdp.Bandwidth) []sdp.Bandwidth {
+  filtered := bandwidths[:0]
+
+  for _, bw := range bandwidths {
+    if bw.Type != "TIAS" {
+      filtered = append(filtered, bw)
+    }
+  }
+
+  return filtered
+}
+
+// ProcessOutgoingSDP rewrites an outgoing offer or answer to include TIAS
+// bandwidth hints before it is handed off to the signalling channel.
+func (p *WebRTCTransport) ProcessOutgoingSDP(sdpText string) (string, error) {
+  p.mu.RLock()
+  maxVideoBitrate, maxAudioBitrate := p.maxVideoBitrate, p.maxAudioBitrate
+
+  trackMaxBitrate := make(map[uint32]uint64, len(p.trackMaxBitrate))
+  for ssrc, bps := range p.trackMaxBitrate {
+    trackMaxBitrate[ssrc] = bps
+  }
+  p.mu.RUnlock()
+
+  out, err := addBandwidthHints(sdpText, maxVideoBitrate, maxAudioBitrate, trackMaxBitrate)
+
+  return out, errors.Annotate(err, "process outgoing sdp")
+}
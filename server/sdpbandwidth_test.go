@@ -0,0 +1,70 @@
+package server
+
+import (
+  "strings"
+  "testing"
+)
+
+const testSDPTemplate = `v=0
+o=- 0 0 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=audio 9 UDP/TLS/RTP/SAVPF 111
+c=IN IP4 0.0.0.0
+a=rtpmap:111 opus/48000/2
+a=ssrc:1111 cname:audio
+m=video 9 UDP/TLS/RTP/SAVPF 96
+c=IN IP4 0.0.0.0
+a=rtpmap:96 VP8/90000
+a=ssrc:2222 cname:video
+`
+
+func TestAddBandwidthHints_DefaultsPerKind(t *testing.T) {
+  out, err := addBandwidthHints(testSDPTemplate, 2_000_000, 64_000, nil)
+  if err != nil {
+    t.Fatalf("addBandwidthHints: %s", err)
+  }
+
+  if !strings.Contains(out, "b=TIAS:64000") {
+    t.Fatalf("expected audio TIAS hint 64000, got:\n%s", out)
+  }
+
+  if !strings.Contains(out, "b=TIAS:2000000") {
+    t.Fatalf("expected video TIAS hint 2000000, got:\n%s", out)
+  }
+}
+
+func TestAddBandwidthHints_PerSSRCOverride(t *testing.T) {
+  out, err := addBandwidthHints(testSDPTemplate, 2_000_000, 64_000, map[uint32]uint64{2222: 300_000})
+  if err != nil {
+    t.Fatalf("addBandwidthHints: %s", err)
+  }
+
+  if !strings.Contains(out, "b=TIAS:300000") {
+    t.Fatalf("expected overridden video TIAS hint 300000, got:\n%s", out)
+  }
+
+  if !strings.Contains(out, "b=TIAS:64000") {
+    t.Fatalf("expected audio track to keep the per-kind default 64000, got:\n%s", out)
+  }
+
+  if strings.Contains(out, "b=TIAS:2000000") {
+    t.Fatalf("overridden video section should not also carry the per-kind default, got:\n%s", out)
+  }
+}
+
+func TestAddBandwidthHints_NoDuplicateTIAS(t *testing.T) {
+  once, err := addBandwidthHints(testSDPTemplate, 2_000_000, 64_000, nil)
+  if err != nil {
+    t.Fatalf("addBandwidthHints: %s", err)
+  }
+
+  twice, err := addBandwidthHints(once, 2_000_000, 64_000, nil)
+  if err != nil {
+    t.Fatalf("addBandwidthHints (second pass): %s", err)
+  }
+
+  if n := strings.Count(twice, "b=TIAS:"); n != 2 {
+    t.Fatalf("expected exactly 2 TIAS lines after re-running on an already-hinted SDP, got %d:\n%s", n, twice)
+  }
+}
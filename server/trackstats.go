@@ -0,0 +1,164 @@
+package server
+
+import (
+  "math"
+  "sync"
+  "time"
+
+  "github.com/pion/rtcp"
+  "github.com/pion/rtp"
+  "github.com/prometheus/client_golang/prometheus"
+  "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// TrackStats is a snapshot of the quality telemetry collected for one
+// remote track.
+type TrackStats struct {
+  Jitter         float64       // RFC 3550 interarrival jitter, in clock-rate units
+  FractionLost   uint8         // from the most recent ReceiverReport
+  CumulativeLost uint32        // from the most recent ReceiverReport
+  RTT            time.Duration // derived from the most recent SR/RR round trip
+  Bitrate        uint64        // most recent aggregated subscriber estimate, if any
+}
+
+// trackStats accumulates jitter (from the RTP ingest loop) and SR/RR based
+// round-trip time (from the RTCP reader loop) for a single remote track.
+// Both loops run concurrently, so all access goes through mu.
+type trackStats struct {
+  mu sync.Mutex
+
+  clockRate uint32
+
+  haveArrival bool
+  lastArrival time.Time
+  lastRTPTime uint32
+  jitter      float64
+
+  fractionLost   uint8
+  cumulativeLost uint32
+
+  srNTPTime uint64
+  srTime    time.Time
+
+  rtt time.Duration
+}
+
+func newTrackStats(clockRate uint32) *trackStats {
+  return &trackStats{clockRate: clockRate}
+}
+
+// updateJitter implements RFC 3550 Appendix A.8: on each packet, compute
+// the difference between how far apart two packets arrived and how far
+// apart their RTP timestamps claim they were sent, then smooth it into a
+// running estimate with a gain of 1/16.
+func (s *trackStats) updateJitter(pkt *rtp.Packet, arrival time.Time) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  if !s.haveArrival {
+    s.haveArrival = true
+    s.lastArrival = arrival
+    s.lastRTPTime = pkt.Timestamp
+
+    return
+  }
+
+  if s.clockRate == 0 {
+    return
+  }
+
+  arrivalUnits := arrival.Sub(s.lastArrival).Seconds() * float64(s.clockRate)
+  rtpUnits := float64(int32(pkt.Timestamp - s.lastRTPTime))
+
+  d := math.Abs(arrivalUnits - rtpUnits)
+  s.jitter += (d - s.jitter) / 16
+
+  s.lastArrival = arrival
+  s.lastRTPTime = pkt.Timestamp
+
+  prometheusTrackJitter.Observe(s.jitter)
+}
+
+// updateSenderReport records the fields of an incoming SR needed to derive
+// RTT once the corresponding RR comes back referencing it via LSR/DLSR.
+func (s *trackStats) updateSenderReport(sr *rtcp.SenderReport) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  s.srNTPTime = sr.NTPTime
+  s.srTime = time.Now()
+}
+
+// updateReceiverReport records loss counters and, if the report references
+// the SR last recorded by updateSenderReport (a non-zero LastSenderReport
+// matching its compact NTP form),
+// computes the round trip time as:
+//
+//	RTT = (time since that SR was received) - DLSR
+func (s *trackStats) updateReceiverReport(rr *rtcp.ReceptionReport) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  s.fractionLost = rr.FractionLost
+  s.cumulativeLost = rr.TotalLost
+
+  prometheusTrackFractionLost.Observe(float64(rr.FractionLost) / 255)
+
+  if rr.LastSenderReport == 0 || rr.LastSenderReport != ntpShort(s.srNTPTime) {
+    return
+  }
+
+  dlsr := time.Duration(rr.Delay) * time.Second / 65536
+
+  rtt := time.Since(s.srTime) - dlsr
+  if rtt < 0 {
+    return
+  }
+
+  s.rtt = rtt
+
+  prometheusTrackRTT.Observe(rtt.Seconds())
+}
+
+// ntpShort extracts the middle 32 bits of a 64-bit NTP timestamp, which is
+// the compact form SR/RR exchange as "LSR"/"last SR".
+func ntpShort(ntp uint64) uint32 {
+  return uint32(ntp >> 16)
+}
+
+func (s *trackStats) snapshot(bitrate uint64) TrackStats {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  return TrackStats{
+    Jitter:         s.jitter,
+    FractionLost:   s.fractionLost,
+    CumulativeLost: s.cumulativeLost,
+    RTT:            s.rtt,
+    Bitrate:        bitrate,
+  }
+}
+
+var (
+  prometheusTrackJitter = promauto.NewHistogram(prometheus.HistogramOpts{
+    Namespace: "peercalls",
+    Subsystem: "track",
+    Name:      "jitter",
+    Help:      "Interarrival jitter of remote tracks, in clock-rate units",
+    Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+  })
+  prometheusTrackRTT = promauto.NewHistogram(prometheus.HistogramOpts{
+    Namespace: "peercalls",
+    Subsystem: "track",
+    Name:      "rtt_seconds",
+    Help:      "Round-trip time of remote tracks, derived from SR/RR",
+    Buckets:   prometheus.DefBuckets,
+  })
+  prometheusTrackFractionLost = promauto.NewHistogram(prometheus.HistogramOpts{
+    Namespace: "peercalls",
+    Subsystem: "track",
+    Name:      "fraction_lost",
+    Help:      "Fraction of packets lost, as reported in the most recent ReceiverReport",
+    Buckets:   prometheus.LinearBuckets(0, 0.05, 10),
+  })
+)
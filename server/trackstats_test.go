@@ -0,0 +1,79 @@
+package server
+
+import (
+  "testing"
+  "time"
+
+  "github.com/pion/rtcp"
+  "github.com/pion/rtp"
+)
+
+func TestTrackStats_UpdateJitter(t *testing.T) {
+  s := newTrackStats(90000)
+
+  start := time.Now()
+
+  // First packet only seeds lastArrival/lastRTPTime; RFC 3550 jitter has no
+  // defined value until a second packet arrives.
+  s.updateJitter(&rtp.Packet{Header: rtp.Header{Timestamp: 0}}, start)
+
+  if s.jitter != 0 {
+    t.Fatalf("expected zero jitter after a single packet, got %f", s.jitter)
+  }
+
+  // Arrival spacing matches RTP timestamp spacing exactly (90000 units/s
+  // clock rate, 1 second apart): zero transit time difference, so jitter
+  // stays at zero.
+  s.updateJitter(&rtp.Packet{Header: rtp.Header{Timestamp: 90000}}, start.Add(time.Second))
+
+  if s.jitter != 0 {
+    t.Fatalf("expected jitter to stay zero for evenly paced packets, got %f", s.jitter)
+  }
+
+  // A packet that arrives 0.5s late relative to its RTP timestamp
+  // introduces a transit difference of 45000 clock units, smoothed in at
+  // a gain of 1/16.
+  s.updateJitter(&rtp.Packet{Header: rtp.Header{Timestamp: 180000}}, start.Add(2500*time.Millisecond))
+
+  want := 45000.0 / 16
+  if s.jitter != want {
+    t.Fatalf("expected jitter %f, got %f", want, s.jitter)
+  }
+}
+
+func TestTrackStats_SenderReceiverReportRTT(t *testing.T) {
+  s := newTrackStats(90000)
+
+  s.updateSenderReport(&rtcp.SenderReport{NTPTime: 0x00000001_80000000})
+
+  // DLSR of 0.25s, observed as if the RR arrived 1s after the SR.
+  s.srTime = time.Now().Add(-time.Second)
+
+  s.updateReceiverReport(&rtcp.ReceptionReport{
+    FractionLost:     5,
+    TotalLost:        10,
+    LastSenderReport: ntpShort(0x00000001_80000000),
+    Delay:            1 << 14, // 0.25s in Q16 units (16384/65536)
+  })
+
+  if s.fractionLost != 5 || s.cumulativeLost != 10 {
+    t.Fatalf("expected loss counters to be recorded, got fractionLost=%d cumulativeLost=%d", s.fractionLost, s.cumulativeLost)
+  }
+
+  if s.rtt < 700*time.Millisecond || s.rtt > 800*time.Millisecond {
+    t.Fatalf("expected RTT near 750ms (1s elapsed - 0.25s DLSR), got %s", s.rtt)
+  }
+}
+
+func TestTrackStats_ReceiverReportIgnoredWithoutMatchingSenderReport(t *testing.T) {
+  s := newTrackStats(90000)
+
+  s.updateReceiverReport(&rtcp.ReceptionReport{
+    FractionLost:     1,
+    LastSenderReport: 12345,
+  })
+
+  if s.rtt != 0 {
+    t.Fatalf("expected RTT to stay zero when LastSenderReport doesn't match the last recorded SR, got %s", s.rtt)
+  }
+}
@@ -18,6 +18,7 @@ type TrackInfo struct {
   StreamID string
   Kind     webrtc.RTPCodecType
   Mid      string
+  Codec    webrtc.RTPCodecCapability
 }
 
 type TrackEventType uint8
@@ -25,17 +26,30 @@ type TrackEventType uint8
 const (
   TrackEventTypeAdd TrackEventType = iota + 1
   TrackEventTypeRemove
+  // TrackEventTypeCodecChange is emitted when a remote track's payload
+  // type changes mid-stream (e.g. a publisher renegotiating VP8 to VP9 on
+  // the same SSRC), so downstream forwarders know to recreate their local
+  // track with the new codec capability.
+  TrackEventTypeCodecChange
 )
 
+// errUnsupportedPayloadType is returned when an RTP packet's payload type
+// does not match any codec negotiated for the receiver, so the packet
+// cannot be labelled (and therefore must not be forwarded).
+var errUnsupportedPayloadType = errors.New("unsupported payload type")
+
 type TrackEvent struct {
   TrackInfo
   Type TrackEventType
 }
 
 type WebRTCTransportFactory struct {
-  loggerFactory LoggerFactory
-  iceServers    []ICEServer
-  webrtcAPI     *webrtc.API
+  loggerFactory   LoggerFactory
+  iceServers      []ICEServer
+  webrtcAPI       *webrtc.API
+  pliInterval     time.Duration
+  maxVideoBitrate uint64
+  maxAudioBitrate uint64
 }
 
 func NewWebRTCTransportFactory(
@@ -103,57 +117,15 @@ func NewWebRTCTransportFactory(
 
   var mediaEngine webrtc.MediaEngine
 
-  RegisterCodecs(&mediaEngine, sfuConfig.JitterBuffer)
+  RegisterCodecs(&mediaEngine)
 
   api := webrtc.NewAPI(
     webrtc.WithMediaEngine(&mediaEngine),
     webrtc.WithSettingEngine(settingEngine),
   )
 
-  return &WebRTCTransportFactory{loggerFactory, iceServers, api}
-}
-
-func RegisterCodecs(mediaEngine *webrtc.MediaEngine, jitterBufferEnabled bool) {
-  rtcpfb := []webrtc.RTCPFeedback{
-    {
-      Type: webrtc.TypeRTCPFBGoogREMB,
-    },
-    // webrtc.RTCPFeedback{
-    // 	Type:      webrtc.TypeRTCPFBCCM,
-    // 	Parameter: "fir",
-    // },
-
-    // https://tools.ietf.org/html/rfc4585#section-4.2
-    // "pli" indicates the use of Picture Loss Indication feedback as defined
-    // in Section 6.3.1.
-    {
-      Type:      webrtc.TypeRTCPFBNACK,
-      Parameter: "pli",
-    },
-  }
-
-  if jitterBufferEnabled {
-    // The feedback type "nack", without parameters, indicates use of the
-    // Generic NACK feedback format as defined in Section 6.2.1.
-    rtcpfb = append(rtcpfb, webrtc.RTCPFeedback{
-      Type:      webrtc.TypeRTCPFBNACK,
-      Parameter: "",
-    })
-  }
-
-  // s.mediaEngine.RegisterCodec(webrtc.NewRTPH264CodecExt(webrtc.DefaultPayloadTypeH264, 90000, rtcpfb, IOSH264Fmtp))
-  // s.mediaEngine.RegisterCodec(webrtc.NewRTPVP9Codec(webrtc.DefaultPayloadTypeVP9, 90000))
-  if err := mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
-    RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: "video/VP8", ClockRate: 90000, Channels: 0, SDPFmtpLine: "", RTCPFeedback: rtcpfb},
-    PayloadType:        96,
-  }, webrtc.RTPCodecTypeVideo); err != nil {
-    panic(err)
-  }
-  if err := mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
-    RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: "audio/opus", ClockRate: 48000, Channels: 2, SDPFmtpLine: "minptime=10;useinbandfec=1", RTCPFeedback: nil},
-    PayloadType:        111,
-  }, webrtc.RTPCodecTypeAudio); err != nil {
-    panic(err)
+  return &WebRTCTransportFactory{
+    loggerFactory, iceServers, api, sfuConfig.PLIInterval, sfuConfig.MaxVideoBitrate, sfuConfig.MaxAudioBitrate,
   }
 }
 
@@ -176,6 +148,37 @@ type WebRTCTransport struct {
 
   localTracks  map[uint32]localTrackInfo
   remoteTracks map[uint32]remoteTrackInfo
+
+  pliInterval   time.Duration
+  pliSchedulers map[uint32]*pliScheduler
+
+  bitrateCoordinators map[uint32]*bitrateCoordinator
+
+  // bitrateEstimators holds, for each locally-published remote track this
+  // transport owns (keyed by SSRC), the bitrate estimator of every
+  // subscriber forwarding it (keyed by subscriber client ID). It is only
+  // ever populated by subscriber transports calling
+  // registerBitrateEstimator on the publisher they're forwarding from.
+  // SSRCs are only required to be unique within one peer connection, so two
+  // unrelated publishers can legitimately pick the same one; scoping this
+  // map to the owning transport instead of a process-wide registry keyed
+  // by SSRC alone means that can never cause a collision. getTrackCache
+  // and AddTrack's publisher parameter scope the packet cache and bitrate
+  // estimator registration the same way, for the same reason.
+  bitrateEstimators map[uint32]map[string]*bitrateEstimator
+
+  // maxVideoBitrate and maxAudioBitrate are the TIAS caps applied to a
+  // local track of that kind when trackMaxBitrate has no per-SSRC override
+  // for it.
+  maxVideoBitrate uint64
+  maxAudioBitrate uint64
+
+  // trackMaxBitrate holds a TIAS override set via SetMaxBitrate, keyed by
+  // senderSSRC (the wire SSRC that shows up in this connection's own SDP),
+  // not by the publisher's original SSRC - so throttling one publisher's
+  // track never clobbers the bandwidth hint of every other local track of
+  // the same kind on this connection.
+  trackMaxBitrate map[uint32]uint64
 }
 
 var _ Transport = &WebRTCTransport{}
@@ -206,7 +209,22 @@ func (f WebRTCTransportFactory) NewWebRTCTransport(clientID string) (*WebRTCTran
     return nil, errors.Annotate(err, "new peer connection")
   }
 
-  return NewWebRTCTransport(f.loggerFactory, clientID, true, peerConnection)
+  transport, err := NewWebRTCTransport(f.loggerFactory, clientID, true, peerConnection)
+  if err != nil {
+    return nil, err
+  }
+
+  transport.pliInterval = f.pliInterval
+
+  if f.maxVideoBitrate > 0 {
+    transport.maxVideoBitrate = f.maxVideoBitrate
+  }
+
+  if f.maxAudioBitrate > 0 {
+    transport.maxAudioBitrate = f.maxAudioBitrate
+  }
+
+  return transport, nil
 }
 
 func NewWebRTCTransport(
@@ -278,6 +296,16 @@ func NewWebRTCTransport(
 
     localTracks:  map[uint32]localTrackInfo{},
     remoteTracks: map[uint32]remoteTrackInfo{},
+
+    pliInterval:   defaultPLIInterval,
+    pliSchedulers: map[uint32]*pliScheduler{},
+
+    bitrateCoordinators: map[uint32]*bitrateCoordinator{},
+    bitrateEstimators:   map[uint32]map[string]*bitrateEstimator{},
+
+    maxVideoBitrate: defaultMaxVideoBitrate,
+    maxAudioBitrate: defaultMaxAudioBitrate,
+    trackMaxBitrate: map[uint32]uint64{},
   }
   peerConnection.OnTrack(transport.handleTrack)
 
@@ -299,6 +327,7 @@ type localTrackInfo struct {
   transceiver *webrtc.RTPTransceiver
   sender      *webrtc.RTPSender
   track       *webrtc.TrackLocalStaticRTP
+  bitrate     *bitrateEstimator
 }
 
 type remoteTrackInfo struct {
@@ -306,6 +335,8 @@ type remoteTrackInfo struct {
   transceiver *webrtc.RTPTransceiver
   receiver    *webrtc.RTPReceiver
   track       *webrtc.TrackRemote
+  cache       *packetCache
+  stats       *trackStats
 }
 
 func (p *WebRTCTransport) Close() error {
@@ -354,6 +385,7 @@ func (p *WebRTCTransport) WriteRTP(packet *rtp.Packet) (bytes int, err error) {
 
   prometheusRTPPacketsSent.Inc()
   prometheusRTPPacketsSentBytes.Add(float64(packet.MarshalSize()))
+  pta.bitrate.addSentBytes(packet.MarshalSize())
 
   return packet.MarshalSize(), nil
 }
@@ -363,6 +395,10 @@ func (p *WebRTCTransport) RemoveTrack(ssrc uint32) error {
   pta, ok := p.localTracks[ssrc]
   if ok {
     delete(p.localTracks, ssrc)
+
+    if wireSSRC, wireOK := senderSSRC(pta.sender); wireOK {
+      delete(p.trackMaxBitrate, wireSSRC)
+    }
   }
   p.mu.Unlock()
 
@@ -380,8 +416,81 @@ func (p *WebRTCTransport) RemoveTrack(ssrc uint32) error {
   return nil
 }
 
-func (p *WebRTCTransport) AddTrack(ssrc uint32, id string, streamId string) error {
-  track, err := webrtc.NewTrackLocalStaticRTP(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8}, id, streamId)
+// senderSSRC returns the SSRC pion's RTPSender assigned to its (first)
+// encoding of a local track - the value that actually ends up in this
+// connection's own outgoing "a=ssrc" line. It is unrelated to the
+// publisher's original SSRC used everywhere else to identify the same
+// forwarded track (localTracks, AddTrack's ssrc parameter, and so on),
+// since TrackLocalStaticRTP rewrites Header.SSRC on every packet it
+// forwards to this connection's own value.
+func senderSSRC(sender *webrtc.RTPSender) (uint32, bool) {
+  encodings := sender.GetParameters().Encodings
+  if len(encodings) == 0 {
+    return 0, false
+  }
+
+  return uint32(encodings[0].SSRC), true
+}
+
+// SetMaxBitrate caps the local track identified by ssrc at bps, by
+// rewriting its sender's encoding parameters, and renegotiates so the
+// subscriber's next offer/answer carries a matching TIAS hint scoped to
+// that SSRC alone, leaving every other local track's hint untouched. It's
+// meant to be driven by the REMB/TWCC bitrate estimator: throttle a
+// specific publisher once its subscribers report congestion.
+func (p *WebRTCTransport) SetMaxBitrate(ssrc uint32, bps uint64) error {
+  p.mu.RLock()
+  pta, ok := p.localTracks[ssrc]
+  p.mu.RUnlock()
+
+  if !ok {
+    return errors.Errorf("track %d not found", ssrc)
+  }
+
+  params := pta.sender.GetParameters()
+
+  for i := range params.Encodings {
+    params.Encodings[i].MaxBitrate = bps
+  }
+
+  if err := pta.sender.SetParameters(params); err != nil {
+    return errors.Annotate(err, "set sender parameters")
+  }
+
+  // addBandwidthHints keys its per-SSRC override off the SSRC that shows
+  // up in this connection's own SDP, which is senderSSRC, not ssrc.
+  if wireSSRC, ok := senderSSRC(pta.sender); ok {
+    p.mu.Lock()
+    p.trackMaxBitrate[wireSSRC] = bps
+    p.mu.Unlock()
+  }
+
+  p.signaller.Negotiate()
+
+  return nil
+}
+
+// AddTrack creates a local track that forwards media to this transport's
+// peer, using codec for the wire format. codec normally comes from the
+// TrackInfo of the remote track being forwarded (see
+// WebRTCTransport.RemoteTracks), so the subscriber always advertises the
+// publisher's actual codec instead of a hardcoded one. publisher is the
+// WebRTCTransport that owns the remote track identified by ssrc; it is
+// used to reach that track's packet cache for NACK retransmission and to
+// register this subscriber's bitrate estimator (see bitrateEstimators for
+// why both are scoped to the publisher).
+func (p *WebRTCTransport) AddTrack(
+  publisher *WebRTCTransport, ssrc uint32, id string, streamId string, codec webrtc.RTPCodecCapability,
+) error {
+  if !isSupportedCodec(codec.MimeType) {
+    return errors.Errorf("unsupported codec for track %s: %s", id, codec.MimeType)
+  }
+
+  if !subscriberSupportsCodec(p.peerConnection.RemoteDescription(), codec.MimeType) {
+    return errors.Errorf("subscriber %s does not support codec %s for track %s", p.clientID, codec.MimeType, id)
+  }
+
+  track, err := webrtc.NewTrackLocalStaticRTP(codec, id, streamId)
   if err != nil {
     return errors.Annotate(err, "new track")
   }
@@ -397,10 +506,18 @@ func (p *WebRTCTransport) AddTrack(ssrc uint32, id string, streamId string) erro
     p.signaller.SendTransceiverRequest(track.Kind(), webrtc.RTPTransceiverDirectionRecvonly)
   }
 
+  bitrate := newBitrateEstimator()
+  publisher.registerBitrateEstimator(ssrc, p.clientID, bitrate)
+
   p.wg.Add(1)
 
   go func() {
     defer p.wg.Done()
+    // Covers abrupt disconnects (ICE failure, browser crash) as well as an
+    // explicit RemoveTrack: either way, removing the sender causes
+    // ReadRTCP to error out and this goroutine to return, so the
+    // registration never outlives the track it belongs to.
+    defer publisher.unregisterBitrateEstimator(ssrc, p.clientID)
 
     for {
       rtcpPackets, _, err := sender.ReadRTCP()
@@ -411,6 +528,16 @@ func (p *WebRTCTransport) AddTrack(ssrc uint32, id string, streamId string) erro
       for _, rtcpPacket := range rtcpPackets {
         p.rtcpLog.Printf("[%s] ReadRTCP: %s", p.clientID, rtcpPacket)
         prometheusRTCPPacketsReceived.Inc()
+
+        switch fb := rtcpPacket.(type) {
+        case *rtcp.TransportLayerNack:
+          p.handleNack(publisher, ssrc, track, fb)
+        case *rtcp.ReceiverEstimatedMaximumBitrate:
+          bitrate.updateREMB(fb)
+        case *rtcp.TransportCCFeedback:
+          bitrate.updateTWCC(fb)
+        }
+
         p.rtcpCh <- rtcpPacket
       }
     }
@@ -432,15 +559,218 @@ func (p *WebRTCTransport) AddTrack(ssrc uint32, id string, streamId string) erro
     StreamID: track.StreamID(),
     Kind:     track.Kind(),
     Mid:      "",
+    Codec:    codec,
   }
 
   p.mu.Lock()
-  p.localTracks[ssrc] = localTrackInfo{trackInfo, transceiver, sender, track}
+  p.localTracks[ssrc] = localTrackInfo{trackInfo, transceiver, sender, track, bitrate}
   p.mu.Unlock()
 
   return nil
 }
 
+// handleNack re-sends cached RTP packets of the origin track (identified by
+// ssrc, owned by publisher) that a subscriber reports missing via a
+// TransportLayerNack. Misses (packets already evicted from the cache, or
+// never received) are counted but otherwise ignored, since the subscriber
+// will simply miss that frame.
+func (p *WebRTCTransport) handleNack(
+  publisher *WebRTCTransport, ssrc uint32, track *webrtc.TrackLocalStaticRTP, nack *rtcp.TransportLayerNack,
+) {
+  cache, ok := publisher.getTrackCache(ssrc)
+  if !ok {
+    return
+  }
+
+  for _, pair := range nack.Nacks {
+    for _, seq := range pair.PacketList() {
+      pkt, ok := cache.get(seq)
+      if !ok {
+        prometheusPacketCacheMisses.Inc()
+
+        continue
+      }
+
+      prometheusPacketCacheHits.Inc()
+
+      if err := track.WriteRTP(pkt); err != nil {
+        p.log.Printf("[%s] Error retransmitting packet %d for ssrc %d: %s", p.clientID, seq, ssrc, err)
+
+        continue
+      }
+
+      prometheusPacketsRetransmitted.Inc()
+    }
+  }
+}
+
+func (p *WebRTCTransport) addPLIScheduler(ssrc uint32, wireSSRC webrtc.SSRC) {
+  scheduler := newPLIScheduler(p.log, p.clientID, p.WriteRTCP, uint32(wireSSRC), p.pliInterval)
+
+  p.mu.Lock()
+  p.pliSchedulers[ssrc] = scheduler
+  p.mu.Unlock()
+
+  scheduler.start(&p.wg)
+}
+
+func (p *WebRTCTransport) removePLIScheduler(ssrc uint32) {
+  p.mu.Lock()
+  scheduler, ok := p.pliSchedulers[ssrc]
+  delete(p.pliSchedulers, ssrc)
+  p.mu.Unlock()
+
+  if ok {
+    scheduler.stop()
+  }
+}
+
+// RequestKeyframe asks the publisher of the remote track identified by ssrc
+// to send a new keyframe, e.g. because a new subscriber has just attached
+// to an already-flowing track and would otherwise see nothing until the
+// next one arrives on its own.
+func (p *WebRTCTransport) RequestKeyframe(ssrc uint32) error {
+  p.mu.RLock()
+  scheduler, ok := p.pliSchedulers[ssrc]
+  p.mu.RUnlock()
+
+  if !ok {
+    return errors.Errorf("track %d not found", ssrc)
+  }
+
+  scheduler.request()
+
+  return nil
+}
+
+func (p *WebRTCTransport) addBitrateCoordinator(ssrc uint32) {
+  coordinator := newBitrateCoordinator(p.log, p.clientID, p.WriteRTCP, ssrc, func() (uint64, bool) {
+    return p.minEstimatedBitrate(ssrc)
+  })
+
+  p.mu.Lock()
+  p.bitrateCoordinators[ssrc] = coordinator
+  p.mu.Unlock()
+
+  coordinator.start(&p.wg)
+}
+
+func (p *WebRTCTransport) removeBitrateCoordinator(ssrc uint32) {
+  p.mu.Lock()
+  coordinator, ok := p.bitrateCoordinators[ssrc]
+  delete(p.bitrateCoordinators, ssrc)
+  p.mu.Unlock()
+
+  if ok {
+    coordinator.stop()
+    prometheusEstimatedBitrate.DeleteLabelValues(formatSSRC(ssrc))
+  }
+}
+
+// EstimatedBitrate returns the most recently aggregated bandwidth estimate
+// across all subscribers of the remote track identified by ssrc. It
+// returns 0 if no subscriber has reported a fresh estimate yet, which
+// callers should treat as "unknown", not "zero bandwidth available".
+func (p *WebRTCTransport) EstimatedBitrate(ssrc uint32) uint64 {
+  bps, _ := p.minEstimatedBitrate(ssrc)
+
+  return bps
+}
+
+// registerBitrateEstimator associates estimator with the subscriber
+// identified by subscriberID for the remote track identified by ssrc, which
+// must be owned by this transport (i.e. this is the publisher side). It is
+// called by AddTrack on the subscriber's own WebRTCTransport, passing this
+// transport in as the publisher (see bitrateEstimators for why this is
+// scoped per-publisher).
+func (p *WebRTCTransport) registerBitrateEstimator(ssrc uint32, subscriberID string, estimator *bitrateEstimator) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  subscribers, ok := p.bitrateEstimators[ssrc]
+  if !ok {
+    subscribers = map[string]*bitrateEstimator{}
+    p.bitrateEstimators[ssrc] = subscribers
+  }
+
+  subscribers[subscriberID] = estimator
+}
+
+func (p *WebRTCTransport) unregisterBitrateEstimator(ssrc uint32, subscriberID string) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  subscribers, ok := p.bitrateEstimators[ssrc]
+  if !ok {
+    return
+  }
+
+  delete(subscribers, subscriberID)
+
+  if len(subscribers) == 0 {
+    delete(p.bitrateEstimators, ssrc)
+  }
+}
+
+// minEstimatedBitrate returns the lowest fresh estimate among ssrc's
+// subscribers, and false if there are none yet.
+func (p *WebRTCTransport) minEstimatedBitrate(ssrc uint32) (uint64, bool) {
+  p.mu.Lock()
+  estimators := make([]*bitrateEstimator, 0, len(p.bitrateEstimators[ssrc]))
+  for _, estimator := range p.bitrateEstimators[ssrc] {
+    estimators = append(estimators, estimator)
+  }
+  p.mu.Unlock()
+
+  return minBitrateEstimate(estimators)
+}
+
+// getTrackCache returns the packet cache for the remote track identified by
+// ssrc, if this transport currently has one receiving - i.e. it is that
+// track's publisher. Subscribers call this on the publisher's
+// WebRTCTransport (passed into AddTrack), the same per-owner scoping as
+// bitrateEstimators and for the same reason.
+func (p *WebRTCTransport) getTrackCache(ssrc uint32) (*packetCache, bool) {
+  p.mu.RLock()
+  rti, ok := p.remoteTracks[ssrc]
+  p.mu.RUnlock()
+
+  if !ok {
+    return nil, false
+  }
+
+  return rti.cache, true
+}
+
+// lookupCodec finds the negotiated codec for payloadType among the codecs
+// the receiver's transceiver was configured with, returning
+// errUnsupportedPayloadType if none matches.
+func lookupCodec(receiver *webrtc.RTPReceiver, payloadType webrtc.PayloadType) (webrtc.RTPCodecParameters, error) {
+  for _, codec := range receiver.GetParameters().Codecs {
+    if codec.PayloadType == payloadType {
+      return codec, nil
+    }
+  }
+
+  return webrtc.RTPCodecParameters{}, errors.Trace(errUnsupportedPayloadType)
+}
+
+// updateRemoteTrackCodec reflects a mid-stream codec change onto the
+// stored remoteTrackInfo, so later calls to RemoteTracks report the track's
+// current codec, not the one it started with.
+func (p *WebRTCTransport) updateRemoteTrackCodec(ssrc uint32, codec webrtc.RTPCodecCapability) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  rti, ok := p.remoteTracks[ssrc]
+  if !ok {
+    return
+  }
+
+  rti.trackInfo.Codec = codec
+  p.remoteTracks[ssrc] = rti
+}
+
 func (p *WebRTCTransport) addRemoteTrack(rti remoteTrackInfo) {
   p.mu.Lock()
   defer p.mu.Unlock()
@@ -471,6 +801,23 @@ func (p *WebRTCTransport) RemoteTracks() []TrackInfo {
   return list
 }
 
+// TrackStats returns the current quality telemetry (jitter, loss, RTT and
+// estimated bitrate) for the remote track identified by ssrc, and false if
+// no such track is currently receiving.
+func (p *WebRTCTransport) TrackStats(ssrc uint32) (TrackStats, bool) {
+  p.mu.RLock()
+  rti, ok := p.remoteTracks[ssrc]
+  p.mu.RUnlock()
+
+  if !ok {
+    return TrackStats{}, false
+  }
+
+  bitrate, _ := p.minEstimatedBitrate(ssrc)
+
+  return rti.stats.snapshot(bitrate), true
+}
+
 // LocalTracks returns info about sending tracks
 func (p *WebRTCTransport) LocalTracks() []TrackInfo {
   p.mu.Lock()
@@ -494,9 +841,10 @@ func (p *WebRTCTransport) handleTrack(track *webrtc.TrackRemote, receiver *webrt
     StreamID: track.StreamID(),
     Kind:     track.Kind(),
     Mid:      "",
+    Codec:    track.Codec().RTPCodecCapability,
   }
 
-  p.log.Printf("[%s] Remote track: %d", p.clientID, trackInfo.SSRC)
+  p.log.Printf("[%s] Remote track: %d (%s)", p.clientID, trackInfo.SSRC, trackInfo.Codec.MimeType)
 
   start := time.Now()
 
@@ -513,9 +861,18 @@ func (p *WebRTCTransport) handleTrack(track *webrtc.TrackRemote, receiver *webrt
     }
   }
 
-  rti := remoteTrackInfo{trackInfo, transceiver, receiver, track}
+  cache := newPacketCache()
+  stats := newTrackStats(trackInfo.Codec.ClockRate)
+
+  rti := remoteTrackInfo{trackInfo, transceiver, receiver, track, cache, stats}
 
   p.addRemoteTrack(rti)
+
+  if trackInfo.Kind == webrtc.RTPCodecTypeVideo {
+    p.addPLIScheduler(trackInfo.SSRC, track.SSRC())
+    p.addBitrateCoordinator(trackInfo.SSRC)
+  }
+
   p.trackEventsCh <- TrackEvent{
     TrackInfo: trackInfo,
     Type:      TrackEventTypeAdd,
@@ -523,9 +880,42 @@ func (p *WebRTCTransport) handleTrack(track *webrtc.TrackRemote, receiver *webrt
 
   p.wg.Add(1)
 
+  go func() {
+    defer p.wg.Done()
+
+    for {
+      rtcpPackets, _, err := receiver.ReadRTCP()
+      if err != nil {
+        return
+      }
+
+      for _, rtcpPacket := range rtcpPackets {
+        p.rtcpLog.Printf("[%s] ReadRTCP: %s", p.clientID, rtcpPacket)
+        prometheusRTCPPacketsReceived.Inc()
+
+        switch pkt := rtcpPacket.(type) {
+        case *rtcp.SenderReport:
+          stats.updateSenderReport(pkt)
+        case *rtcp.ReceiverReport:
+          for i := range pkt.Reports {
+            if pkt.Reports[i].SSRC == trackInfo.SSRC {
+              stats.updateReceiverReport(&pkt.Reports[i])
+            }
+          }
+        }
+
+        p.rtcpCh <- rtcpPacket
+      }
+    }
+  }()
+
+  p.wg.Add(1)
+
   go func() {
     defer func() {
       p.removeRemoteTrack(trackInfo.SSRC)
+      p.removePLIScheduler(trackInfo.SSRC)
+      p.removeBitrateCoordinator(trackInfo.SSRC)
       p.trackEventsCh <- TrackEvent{
         TrackInfo: trackInfo,
         Type:      TrackEventTypeRemove,
@@ -537,6 +927,8 @@ func (p *WebRTCTransport) handleTrack(track *webrtc.TrackRemote, receiver *webrt
       prometheusWebRTCTracksDuration.Observe(time.Since(start).Seconds())
     }()
 
+    lastPayloadType := track.PayloadType()
+
     for {
       pkt, _, err := track.ReadRTP()
       if err != nil {
@@ -551,9 +943,34 @@ func (p *WebRTCTransport) handleTrack(track *webrtc.TrackRemote, receiver *webrt
         return
       }
 
+      payloadType := webrtc.PayloadType(pkt.PayloadType)
+      if payloadType != lastPayloadType {
+        codec, err := lookupCodec(receiver, payloadType)
+        if err != nil {
+          p.log.Printf(
+            "[%s] Dropping packet with payload type %d on track %d: %+v",
+            p.clientID, payloadType, trackInfo.SSRC, err,
+          )
+
+          continue
+        }
+
+        lastPayloadType = payloadType
+        trackInfo.Codec = codec.RTPCodecCapability
+        p.updateRemoteTrackCodec(trackInfo.SSRC, trackInfo.Codec)
+
+        p.trackEventsCh <- TrackEvent{
+          TrackInfo: trackInfo,
+          Type:      TrackEventTypeCodecChange,
+        }
+      }
+
       prometheusRTPPacketsReceived.Inc()
       prometheusRTPPacketsReceivedBytes.Add(float64(pkt.MarshalSize()))
 
+      cache.add(pkt)
+      stats.updateJitter(pkt, time.Now())
+
       p.rtpLog.Printf("[%s] ReadRTP: %s", p.clientID, pkt)
       p.rtpCh <- pkt
     }
@@ -566,8 +983,52 @@ func (p *WebRTCTransport) Signal(payload map[string]interface{}) error {
   return errors.Annotate(err, "signal")
 }
 
+// SignalChannel forwards outgoing signal payloads from the underlying
+// Signaller, rewriting any SDP offer/answer via ProcessOutgoingSDP before
+// it reaches the caller - this is the actual point at which an outgoing
+// SDP is "handed off to the signalling channel", so it's where the TIAS
+// bandwidth hints from ProcessOutgoingSDP need to be applied for them to
+// ever reach a remote peer.
 func (p *WebRTCTransport) SignalChannel() <-chan Payload {
-  return p.signaller.SignalChannel()
+  out := make(chan Payload)
+
+  p.wg.Add(1)
+
+  go func() {
+    defer p.wg.Done()
+    defer close(out)
+
+    for payload := range p.signaller.SignalChannel() {
+      out <- p.processOutgoingPayload(payload)
+    }
+  }()
+
+  return out
+}
+
+// processOutgoingPayload rewrites the SDP of an outgoing offer/answer via
+// ProcessOutgoingSDP. Payloads carrying anything else (e.g. an ICE
+// candidate) pass through unchanged. Failures are logged and the original,
+// un-hinted SDP is forwarded rather than dropping the payload entirely - a
+// missing bandwidth hint degrades gracefully, a dropped offer/answer does
+// not.
+func (p *WebRTCTransport) processOutgoingPayload(payload Payload) Payload {
+  desc, ok := payload.Signal.(webrtc.SessionDescription)
+  if !ok {
+    return payload
+  }
+
+  sdpText, err := p.ProcessOutgoingSDP(desc.SDP)
+  if err != nil {
+    p.log.Printf("[%s] Error processing outgoing SDP: %+v", p.clientID, err)
+
+    return payload
+  }
+
+  desc.SDP = sdpText
+  payload.Signal = desc
+
+  return payload
 }
 
 func (p *WebRTCTransport) TrackEventsChannel() <-chan TrackEvent {
@@ -0,0 +1,54 @@
+package server
+
+import (
+  "strings"
+  "testing"
+
+  "github.com/pion/webrtc/v3"
+)
+
+// TestSenderSSRC_MatchesGeneratedSDP guards the invariant SetMaxBitrate
+// relies on: the SSRC an RTPSender actually assigns to its encoding (what
+// senderSSRC returns) is the same SSRC that ends up in this connection's
+// own generated SDP, not the unrelated publisher SSRC used to look the
+// local track up in localTracks. Without this, trackMaxBitrate overrides
+// keyed by senderSSRC would never match anything addBandwidthHints finds
+// in a real offer.
+func TestSenderSSRC_MatchesGeneratedSDP(t *testing.T) {
+  pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+  if err != nil {
+    t.Fatalf("new peer connection: %s", err)
+  }
+  defer pc.Close()
+
+  track, err := webrtc.NewTrackLocalStaticRTP(
+    webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000}, "video", "stream",
+  )
+  if err != nil {
+    t.Fatalf("new track: %s", err)
+  }
+
+  sender, err := pc.AddTrack(track)
+  if err != nil {
+    t.Fatalf("add track: %s", err)
+  }
+
+  wireSSRC, ok := senderSSRC(sender)
+  if !ok {
+    t.Fatalf("expected senderSSRC to find an encoding")
+  }
+
+  offer, err := pc.CreateOffer(nil)
+  if err != nil {
+    t.Fatalf("create offer: %s", err)
+  }
+
+  out, err := addBandwidthHints(offer.SDP, 2_000_000, 64_000, map[uint32]uint64{wireSSRC: 300_000})
+  if err != nil {
+    t.Fatalf("addBandwidthHints: %s", err)
+  }
+
+  if !strings.Contains(out, "b=TIAS:300000") {
+    t.Fatalf("expected the override keyed by senderSSRC to apply to the real generated SDP, got:\n%s", out)
+  }
+}